@@ -0,0 +1,37 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/upjet-provider-template/apis/v1beta1"
+)
+
+// A CredentialExtractor extracts the raw credentials referenced by a
+// ProviderConfig.
+type CredentialExtractor interface {
+	Extract(ctx context.Context, client client.Client, pc *v1beta1.ProviderConfig) ([]byte, error)
+}
+
+// A CredentialExtractorFn is a function that satisfies the
+// CredentialExtractor interface.
+type CredentialExtractorFn func(ctx context.Context, client client.Client, pc *v1beta1.ProviderConfig) ([]byte, error)
+
+// Extract calls the function itself.
+func (fn CredentialExtractorFn) Extract(ctx context.Context, client client.Client, pc *v1beta1.ProviderConfig) ([]byte, error) {
+	return fn(ctx, client, pc)
+}
+
+// CommonCredentialExtractor extracts credentials from the sources natively
+// supported by Crossplane, i.e. Secret, Environment and Filesystem.
+func CommonCredentialExtractor() CredentialExtractor {
+	return CredentialExtractorFn(func(ctx context.Context, client client.Client, pc *v1beta1.ProviderConfig) ([]byte, error) {
+		return resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, client, pc.Spec.Credentials.CommonCredentialSelectors)
+	})
+}
@@ -27,9 +27,34 @@ const (
 	errUnmarshalCredentials = "cannot unmarshal template credentials as JSON"
 )
 
+type setupOptions struct {
+	credentials CredentialExtractor
+}
+
+// A SetupOption configures the terraform.SetupFn built by
+// TerraformSetupBuilder.
+type SetupOption func(o *setupOptions)
+
+// WithCredentialExtractor configures the CredentialExtractor used to read the
+// credentials of the referenced ProviderConfig. CommonCredentialExtractor is
+// used by default, and a nil extractor leaves the default in place.
+func WithCredentialExtractor(e CredentialExtractor) SetupOption {
+	return func(o *setupOptions) {
+		if e != nil {
+			o.credentials = e
+		}
+	}
+}
+
 // TerraformSetupBuilder builds Terraform a terraform.SetupFn function which
 // returns Terraform provider setup configuration
-func TerraformSetupBuilder(version, providerSource, providerVersion string) terraform.SetupFn {
+func TerraformSetupBuilder(version, providerSource, providerVersion string, opts ...SetupOption) terraform.SetupFn {
+	so := &setupOptions{
+		credentials: CommonCredentialExtractor(),
+	}
+	for _, o := range opts {
+		o(so)
+	}
 	return func(ctx context.Context, client client.Client, mg resource.Managed) (terraform.Setup, error) {
 		ps := terraform.Setup{
 			Version: version,
@@ -53,7 +78,7 @@ func TerraformSetupBuilder(version, providerSource, providerVersion string) terr
 			return ps, errors.Wrap(err, errTrackUsage)
 		}
 
-		data, err := resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, client, pc.Spec.Credentials.CommonCredentialSelectors)
+		data, err := so.credentials.Extract(ctx, client, pc)
 		if err != nil {
 			return ps, errors.Wrap(err, errExtractCredentials)
 		}
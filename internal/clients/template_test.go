@@ -0,0 +1,140 @@
+/*
+Copyright 2022 Upbound Inc.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/upjet-provider-template/apis/v1beta1"
+)
+
+const (
+	pcName     = "default"
+	secretKey  = "credentials"
+	secretName = "template-creds"
+)
+
+var errBoom = errors.New("boom")
+
+// newMockClient returns a client that serves a ProviderConfig with the
+// supplied credentials source, lets ProviderConfigUsages be created, and
+// serves a Secret holding secretData unless errSecret is set.
+func newMockClient(source xpv1.CredentialsSource, secretData []byte, errSecret error) *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1beta1.ProviderConfig:
+				o.SetName(pcName)
+				o.Spec.Credentials.Source = source
+				o.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: secretName, Namespace: "crossplane-system"},
+					Key:             secretKey,
+				}
+				return nil
+			case *v1beta1.ProviderConfigUsage:
+				return kerrors.NewNotFound(schema.GroupResource{}, "")
+			case *corev1.Secret:
+				if errSecret != nil {
+					return errSecret
+				}
+				o.Data = map[string][]byte{secretKey: secretData}
+				return nil
+			}
+			return errors.Errorf("unexpected object %T", obj)
+		},
+		MockCreate: test.NewMockCreateFn(nil),
+	}
+}
+
+func TestTerraformSetupBuilder(t *testing.T) {
+	errJSON := json.Unmarshal([]byte("not-json"), &map[string]string{})
+
+	type args struct {
+		client client.Client
+		opts   []SetupOption
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DefaultExtractor": {
+			reason: "Credentials should be read from the Secret referenced by the ProviderConfig when no extractor is configured.",
+			args: args{
+				client: newMockClient(xpv1.CredentialsSourceSecret, []byte(`{"username":"admin"}`), nil),
+			},
+		},
+		"NilExtractor": {
+			reason: "A nil extractor should leave the default extractor in place.",
+			args: args{
+				client: newMockClient(xpv1.CredentialsSourceSecret, []byte(`{"username":"admin"}`), nil),
+				opts:   []SetupOption{WithCredentialExtractor(nil)},
+			},
+		},
+		"CustomExtractor": {
+			reason: "A custom extractor should be called with the ProviderConfig instead of the default one.",
+			args: args{
+				client: newMockClient(xpv1.CredentialsSourceSecret, nil, errBoom),
+				opts: []SetupOption{WithCredentialExtractor(CredentialExtractorFn(func(_ context.Context, _ client.Client, pc *v1beta1.ProviderConfig) ([]byte, error) {
+					if pc.GetName() != pcName {
+						return nil, errors.Errorf("unexpected ProviderConfig %q", pc.GetName())
+					}
+					return []byte(`{"username":"admin"}`), nil
+				}))},
+			},
+		},
+		"CustomExtractorError": {
+			reason: "Errors returned by a custom extractor should be wrapped.",
+			args: args{
+				client: newMockClient(xpv1.CredentialsSourceSecret, nil, nil),
+				opts: []SetupOption{WithCredentialExtractor(CredentialExtractorFn(func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig) ([]byte, error) {
+					return nil, errBoom
+				}))},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errExtractCredentials),
+			},
+		},
+		"CustomExtractorInvalidJSON": {
+			reason: "Credentials that are not a JSON object should be rejected.",
+			args: args{
+				client: newMockClient(xpv1.CredentialsSourceSecret, nil, nil),
+				opts: []SetupOption{WithCredentialExtractor(CredentialExtractorFn(func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig) ([]byte, error) {
+					return []byte("not-json"), nil
+				}))},
+			},
+			want: want{
+				err: errors.Wrap(errJSON, errUnmarshalCredentials),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{
+				ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: pcName}},
+			}
+			fn := TerraformSetupBuilder("1.2.1", "hashicorp/null", "3.1.0", tc.args.opts...)
+			_, err := fn(context.Background(), tc.args.client, mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nTerraformSetupBuilder(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}